@@ -14,17 +14,28 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/google/go-querystring/query"
 	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 )
 
 // AppOnlyOAuthSession represents an OAuth session with reddit.com --
 // all authenticated API calls are methods bound to this type.
 type AppOnlyOAuthSession struct {
+	// mu guards Client, TokenExpiry, and RateLimit, all of which are read
+	// and written concurrently by getBodyOnce/refreshToken when the
+	// session is shared across goroutines. Holding it across refreshToken
+	// also serializes token refreshes within this process, so concurrent
+	// callers racing a cold cache mint at most one token instead of each
+	// independently hitting reddit's token endpoint.
+	mu           sync.Mutex
 	Client       *http.Client
 	ClientID     string
 	ClientSecret string
@@ -33,12 +44,54 @@ type AppOnlyOAuthSession struct {
 	UserAgent    string
 	ctx          context.Context
 	Debug        bool
+
+	// RateLimiter gates outgoing requests against reddit's advertised
+	// budget. Defaults to an in-memory limiter; swap in a shared
+	// implementation (e.g. Redis-backed) so that many process instances
+	// using the same client credentials can cooperate on one budget.
+	RateLimiter RateLimiter
+	// RateLimit is the most recently observed rate-limit state.
+	RateLimit RateLimitingInfo
+
+	// BaseURL is the root of reddit's OAuth API, used to build every
+	// request URL. Overridable via WithBaseURL for testing against a
+	// mock server or proxy.
+	BaseURL string
+	// Logger receives diagnostic messages. Defaults to a no-op logger.
+	Logger Logger
+	// RequestCallback, if set, is invoked after every request/response
+	// pair so callers can hook in metrics or tracing.
+	RequestCallback func(*http.Request, *http.Response)
+
+	// Decoder decodes each response body into the caller's struct.
+	// Defaults to encoding/json.
+	Decoder Decoder
+
+	// TokenStore persists the minted OAuth token. Defaults to an
+	// in-memory store; swap in a shared implementation (e.g. Redis-backed
+	// via NewRedisTokenStore) so a fleet of workers using the same client
+	// credentials share one token instead of each minting their own.
+	TokenStore TokenStore
+
+	// Subreddit, User, Post, Comment, and Frontpage namespace geddit's
+	// endpoints by the resource they act on. Prefer these over the
+	// top-level methods of the same name: their methods take a
+	// context.Context, and new endpoints land here going forward.
+	Subreddit *SubredditService
+	User      *UserService
+	Post      *PostService
+	Comment   *CommentService
+	Frontpage *FrontpageService
 }
 
+// defaultBaseURL is reddit's OAuth API root.
+const defaultBaseURL = "https://oauth.reddit.com"
+
 // NewAppOnlyOAuthSession creates a new session for those who want to log into a
-// reddit account via Application Only OAuth.
+// reddit account via Application Only OAuth. Pass Option values to customize
+// the underlying HTTP client, transport, base URL, and more.
 // See https://github.com/reddit/reddit/wiki/OAuth2#application-only-oauth
-func NewAppOnlyOAuthSession(clientID, clientSecret, useragent string, debug bool) (*AppOnlyOAuthSession, error) {
+func NewAppOnlyOAuthSession(clientID, clientSecret, useragent string, debug bool, opts ...Option) (*AppOnlyOAuthSession, error) {
 	s := &AppOnlyOAuthSession{}
 
 	if useragent != "" {
@@ -49,6 +102,10 @@ func NewAppOnlyOAuthSession(clientID, clientSecret, useragent string, debug bool
 
 	s.ClientID = clientID
 	s.ClientSecret = clientSecret
+	s.Debug = debug
+	s.BaseURL = defaultBaseURL
+	s.Logger = noopLogger{}
+	s.Decoder = jsonDecoder{}
 
 	// Set OAuth config
 	s.OAuthConfig = &clientcredentials.Config{
@@ -58,67 +115,198 @@ func NewAppOnlyOAuthSession(clientID, clientSecret, useragent string, debug bool
 	}
 
 	s.ctx = context.Background()
+	s.RateLimiter = NewInMemoryRateLimiter(defaultRateLimitBuffer)
+	s.TokenStore = NewMemoryTokenStore()
+
+	for _, opt := range opts {
+		if err := opt(s); err != nil {
+			return nil, err
+		}
+	}
+
+	s.Subreddit = &SubredditService{session: s}
+	s.User = &UserService{session: s}
+	s.Post = &PostService{session: s}
+	s.Comment = &CommentService{session: s}
+	s.Frontpage = &FrontpageService{session: s}
 
 	return s, nil
 }
 
-// refreshToken should be called internally before each API call
-func (a *AppOnlyOAuthSession) refreshToken() error {
+// refreshToken should be called internally before each API call. It first
+// checks this session's own cached expiry, then the shared TokenStore
+// (which another process may have already refreshed), before minting a
+// new token and writing it back to the store for others to reuse.
+func (a *AppOnlyOAuthSession) refreshToken(ctx context.Context) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
 	// Check if token needs to be refreshed
 	if time.Now().Before(a.TokenExpiry) {
 		return nil
 	}
 
+	// WithTransport and WithConnectionLimit stash a base *http.Client on
+	// a.ctx at construction time (see options.go); carry it over onto the
+	// caller's ctx so a cold mint still honors it while remaining subject
+	// to the caller's own cancellation and deadline.
+	if client := a.ctx.Value(oauth2.HTTPClient); client != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, client)
+	}
+
+	if cached, err := a.TokenStore.Get(ctx, a.ClientID); err == nil && cached != nil && time.Now().Add(tokenRefreshSkew).Before(cached.Expiry) {
+		a.TokenExpiry = cached.Expiry
+		a.Client = oauth2.NewClient(ctx, oauth2.StaticTokenSource(cached))
+		return nil
+	}
+
 	// Fetch OAuth token
-	t, err := a.OAuthConfig.Token(a.ctx)
+	t, err := a.OAuthConfig.Token(ctx)
 	if err != nil {
 		return err
 	}
 	a.TokenExpiry = t.Expiry
+	a.Client = oauth2.NewClient(ctx, oauth2.StaticTokenSource(t))
+
+	if err := a.TokenStore.Set(ctx, a.ClientID, t); err != nil {
+		a.Logger.Printf("geddit: failed to cache oauth token: %v", err)
+	}
 
-	a.Client = a.OAuthConfig.Client(a.ctx)
 	return nil
 }
 
-func (a *AppOnlyOAuthSession) getBody(link string, d interface{}) error {
-	a.refreshToken()
+// getBody fetches link and decodes its JSON body into d, retrying transient
+// failures on a backoff schedule. See RequestOption to tune or disable that
+// behavior for a single call. ctx governs cancellation of the request and
+// of any wait imposed by the RateLimiter; pass context.Background() if the
+// call has no deadline of its own.
+func (a *AppOnlyOAuthSession) getBody(ctx context.Context, link string, d interface{}, opts ...RequestOption) error {
+	cfg := defaultRequestConfig()
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	schedule := cfg.backoff
+	if !cfg.retry {
+		schedule = nil
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = a.getBodyOnce(ctx, link, d)
+		if err == nil {
+			return nil
+		}
+		if attempt >= len(schedule) || !isRetryableError(err) {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		select {
+		case <-time.After(schedule[attempt]):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (a *AppOnlyOAuthSession) getBodyOnce(ctx context.Context, link string, d interface{}) error {
+	a.refreshToken(ctx)
+
+	if ctx.Value(skipRateLimitingKey) == nil {
+		if err := a.RateLimiter.Take(ctx, a.ClientID); err != nil {
+			return err
+		}
+	}
 
 	req, err := http.NewRequest("GET", link, nil)
 	if err != nil {
 		return err
 	}
+	req = req.WithContext(ctx)
 
 	// This is needed to avoid rate limits
 	req.Header.Set("User-Agent", a.UserAgent)
 
-	if a.Client == nil {
+	a.mu.Lock()
+	client := a.Client
+	a.mu.Unlock()
+	if client == nil {
 		return errors.New("OAuth Session lacks HTTP client! Error getting token")
 	}
 
-	resp, err := a.Client.Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
+		if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			return ErrTimeout
+		}
 		return err
 	}
 	defer resp.Body.Close()
 
+	if a.RequestCallback != nil {
+		a.RequestCallback(req, resp)
+	}
+
+	rateLimit := parseRateLimitingInfo(resp.Header)
+	a.mu.Lock()
+	a.RateLimit = rateLimit
+	a.mu.Unlock()
+	a.RateLimiter.Update(rateLimit)
+
 	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
 
 	// DEBUG
 	if a.Debug {
 		fmt.Printf("***DEBUG***\nRequest Body: %s\n***DEBUG***\n\n", body)
 	}
 
-	err = json.Unmarshal(body, d)
-	if err != nil {
-		return err
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		statusErr := classifyStatusError(resp, body)
+		if statusErr == nil {
+			statusErr = fmt.Errorf("geddit: unexpected status %s", resp.Status)
+		}
+		if statusErr == ErrOAuthRevoked {
+			// Force a fresh token on the next call.
+			a.mu.Lock()
+			a.TokenExpiry = time.Time{}
+			a.mu.Unlock()
+		}
+		return statusErr
 	}
 
-	return nil
+	return a.Decoder.Decode(body, d)
+}
+
+// isRetryableError reports whether err represents a transient failure worth
+// retrying. Every error counts as transient except the sentinel errors for a
+// revoked token, a missing, private or quarantined subreddit, and rate
+// limiting, and JSON syntax errors; so network errors, timeouts, 5xx
+// responses and any other unclassified non-2xx status are all retried.
+func isRetryableError(err error) bool {
+	switch err {
+	case ErrOAuthRevoked, ErrSubredditNotFound, ErrSubredditIsPrivate, ErrSubredditIsQuarantined, ErrRateLimited:
+		return false
+	}
+	if _, ok := err.(*json.SyntaxError); ok {
+		return false
+	}
+	return true
 }
 
-// Listing returns a slice of Submission pointers.
+// Listing returns a page of a user's submissions, e.g. their upvoted or
+// submitted listing, along with the tokens needed to fetch the next page.
 // See https://www.reddit.com/dev/api#listings for documentation.
-func (a *AppOnlyOAuthSession) Listing(username, listing string, sort popularitySort, params ListingOptions) ([]*Submission, error) {
+//
+// Deprecated: use session.User.Listing, which takes a context.Context.
+func (a *AppOnlyOAuthSession) Listing(username, listing string, sort popularitySort, params ListingOptions) (*SubmissionListing, error) {
+	return a.userListing(context.Background(), username, listing, sort, params, "", 0)
+}
+
+func (a *AppOnlyOAuthSession) userListing(ctx context.Context, username, listing string, sort popularitySort, params ListingOptions, after string, count int, opts ...RequestOption) (*SubmissionListing, error) {
 	p, err := query.Values(params)
 	if err != nil {
 		return nil, err
@@ -126,17 +314,23 @@ func (a *AppOnlyOAuthSession) Listing(username, listing string, sort popularityS
 	if sort != "" {
 		p.Set("sort", string(sort))
 	}
+	if after != "" {
+		p.Set("after", after)
+		p.Set("count", strconv.Itoa(count))
+	}
 
 	type resp struct {
 		Data struct {
+			After    string
+			Before   string
 			Children []struct {
 				Data *Submission
 			}
 		}
 	}
 	r := &resp{}
-	url := fmt.Sprintf("https://oauth.reddit.com/user/%s/%s?%s", username, listing, p.Encode())
-	err = a.getBody(url, r)
+	url := fmt.Sprintf(a.BaseURL+"/user/%s/%s?%s", username, listing, p.Encode())
+	err = a.getBody(ctx, url, r, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -146,29 +340,57 @@ func (a *AppOnlyOAuthSession) Listing(username, listing string, sort popularityS
 		submissions[i] = child.Data
 	}
 
-	return submissions, nil
+	sl := &SubmissionListing{
+		Submissions: submissions,
+		After:       r.Data.After,
+		Before:      r.Data.Before,
+		Count:       len(submissions),
+	}
+	sl.next = func(ctx context.Context, after string, count int) (*SubmissionListing, error) {
+		next, err := a.userListing(ctx, username, listing, sort, params, after, count, opts...)
+		if err != nil {
+			return nil, err
+		}
+		next.Count += count
+		return next, nil
+	}
+	return sl, nil
 }
 
-func (a *AppOnlyOAuthSession) Upvoted(username string, sort popularitySort, params ListingOptions) ([]*Submission, error) {
+// Upvoted returns a page of the submissions a user has upvoted.
+//
+// Deprecated: use session.User.Upvoted, which takes a context.Context.
+func (a *AppOnlyOAuthSession) Upvoted(username string, sort popularitySort, params ListingOptions) (*SubmissionListing, error) {
 	return a.Listing(username, "upvoted", sort, params)
 }
 
 // AboutRedditor returns a Redditor for the given username using OAuth.
+//
+// Deprecated: use session.User.About, which takes a context.Context.
 func (a *AppOnlyOAuthSession) AboutRedditor(user string) (*Redditor, error) {
+	return a.aboutRedditor(context.Background(), user)
+}
+
+func (a *AppOnlyOAuthSession) aboutRedditor(ctx context.Context, user string, opts ...RequestOption) (*Redditor, error) {
 	type redditor struct {
 		Data Redditor
 	}
 	r := &redditor{}
-	link := fmt.Sprintf("https://oauth.reddit.com/user/%s/about", user)
+	link := fmt.Sprintf(a.BaseURL+"/user/%s/about", user)
 
-	err := a.getBody(link, r)
+	err := a.getBody(ctx, link, r, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return &r.Data, nil
 }
 
+// Deprecated: use session.User.Trophies, which takes a context.Context.
 func (a *AppOnlyOAuthSession) UserTrophies(user string) ([]*Trophy, error) {
+	return a.userTrophies(context.Background(), user)
+}
+
+func (a *AppOnlyOAuthSession) userTrophies(ctx context.Context, user string, opts ...RequestOption) ([]*Trophy, error) {
 	type trophyData struct {
 		Data struct {
 			Trophies []struct {
@@ -178,8 +400,8 @@ func (a *AppOnlyOAuthSession) UserTrophies(user string) ([]*Trophy, error) {
 	}
 
 	t := &trophyData{}
-	url := fmt.Sprintf("https://oauth.reddit.com/api/v1/user/%s/trophies", user)
-	err := a.getBody(url, t)
+	url := fmt.Sprintf(a.BaseURL+"/api/v1/user/%s/trophies", user)
+	err := a.getBody(ctx, url, t, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -192,29 +414,45 @@ func (a *AppOnlyOAuthSession) UserTrophies(user string) ([]*Trophy, error) {
 }
 
 // AboutSubreddit returns a subreddit for the given subreddit name using OAuth.
+//
+// Deprecated: use session.Subreddit.About, which takes a context.Context.
 func (a *AppOnlyOAuthSession) AboutSubreddit(name string) (*Subreddit, error) {
+	return a.aboutSubreddit(context.Background(), name)
+}
+
+func (a *AppOnlyOAuthSession) aboutSubreddit(ctx context.Context, name string, opts ...RequestOption) (*Subreddit, error) {
 	type subreddit struct {
 		Data Subreddit
 	}
 	sr := &subreddit{}
-	link := fmt.Sprintf("https://oauth.reddit.com/r/%s/about", name)
+	link := fmt.Sprintf(a.BaseURL+"/r/%s/about", name)
 
-	err := a.getBody(link, sr)
+	err := a.getBody(ctx, link, sr, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return &sr.Data, nil
 }
 
-// Comments returns the comments for a given Submission using OAuth.
+// Comments returns the comments for a given Submission using OAuth. Unlike
+// the other listing endpoints, reddit's comment tree has no after/before
+// tokens to page through -- "more" objects nest deeper into the same
+// response instead -- so this returns a flat slice rather than a
+// SubmissionListing-style paginated result.
+//
+// Deprecated: use session.Post.Comments, which takes a context.Context.
 func (a *AppOnlyOAuthSession) Comments(h *Submission, sort popularitySort, params ListingOptions) ([]*Comment, error) {
+	return a.comments(context.Background(), h.ID, sort, params)
+}
+
+func (a *AppOnlyOAuthSession) comments(ctx context.Context, id string, sort popularitySort, params ListingOptions, opts ...RequestOption) ([]*Comment, error) {
 	p, err := query.Values(params)
 	if err != nil {
 		return nil, err
 	}
 	var c interface{}
-	link := fmt.Sprintf("https://oauth.reddit.com/comments/%s?%s", h.ID, p.Encode())
-	err = a.getBody(link, &c)
+	link := fmt.Sprintf(a.BaseURL+"/comments/%s?%s", id, p.Encode())
+	err = a.getBody(ctx, link, &c, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -223,14 +461,26 @@ func (a *AppOnlyOAuthSession) Comments(h *Submission, sort popularitySort, param
 	return helper.comments, nil
 }
 
-// SubredditSubmissions returns the submissions on the given subreddit using OAuth.
-func (a *AppOnlyOAuthSession) SubredditSubmissions(subreddit string, sort popularitySort, params ListingOptions) ([]*Submission, error) {
+// SubredditSubmissions returns a page of submissions on the given
+// subreddit, along with the tokens needed to fetch the next page.
+//
+// Deprecated: use session.Subreddit.Submissions, which takes a
+// context.Context.
+func (a *AppOnlyOAuthSession) SubredditSubmissions(subreddit string, sort popularitySort, params ListingOptions) (*SubmissionListing, error) {
+	return a.subredditSubmissions(context.Background(), subreddit, sort, params, "", 0)
+}
+
+func (a *AppOnlyOAuthSession) subredditSubmissions(ctx context.Context, subreddit string, sort popularitySort, params ListingOptions, after string, count int, opts ...RequestOption) (*SubmissionListing, error) {
 	v, err := query.Values(params)
 	if err != nil {
 		return nil, err
 	}
+	if after != "" {
+		v.Set("after", after)
+		v.Set("count", strconv.Itoa(count))
+	}
 
-	baseUrl := "https://oauth.reddit.com"
+	baseUrl := a.BaseURL
 
 	// If subbreddit given, add to URL
 	if subreddit != "" {
@@ -241,6 +491,8 @@ func (a *AppOnlyOAuthSession) SubredditSubmissions(subreddit string, sort popula
 
 	type Response struct {
 		Data struct {
+			After    string
+			Before   string
 			Children []struct {
 				Data *Submission
 			}
@@ -248,7 +500,7 @@ func (a *AppOnlyOAuthSession) SubredditSubmissions(subreddit string, sort popula
 	}
 
 	r := new(Response)
-	err = a.getBody(redditURL, r)
+	err = a.getBody(ctx, redditURL, r, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -258,10 +510,30 @@ func (a *AppOnlyOAuthSession) SubredditSubmissions(subreddit string, sort popula
 		submissions[i] = child.Data
 	}
 
-	return submissions, nil
+	sl := &SubmissionListing{
+		Submissions: submissions,
+		After:       r.Data.After,
+		Before:      r.Data.Before,
+		Count:       len(submissions),
+	}
+	sl.next = func(ctx context.Context, after string, count int) (*SubmissionListing, error) {
+		next, err := a.subredditSubmissions(ctx, subreddit, sort, params, after, count, opts...)
+		if err != nil {
+			return nil, err
+		}
+		next.Count += count
+		return next, nil
+	}
+	return sl, nil
 }
 
-// Frontpage returns the submissions on the default reddit frontpage using OAuth.
-func (a *AppOnlyOAuthSession) Frontpage(sort popularitySort, params ListingOptions) ([]*Submission, error) {
+// FrontpageSubmissions returns a page of submissions on the default reddit
+// frontpage. It was named Frontpage prior to the introduction of the
+// session.Frontpage field (see FrontpageService), which that name now
+// refers to.
+//
+// Deprecated: use session.Frontpage.Hot or session.Frontpage.Sorted, which
+// take a context.Context.
+func (a *AppOnlyOAuthSession) FrontpageSubmissions(sort popularitySort, params ListingOptions) (*SubmissionListing, error) {
 	return a.SubredditSubmissions("", sort, params)
 }
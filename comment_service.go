@@ -0,0 +1,17 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+// CommentService groups comment-scoped endpoints. Obtain one from
+// AppOnlyOAuthSession.Comment rather than constructing it directly.
+//
+// Comment fetching today is reached through PostService.Comments, since
+// reddit only exposes a comment tree by post ID. This service is the home
+// for comment-level operations reddit's API supports but geddit doesn't
+// implement yet (reply, edit, vote), reserved so they can be added here
+// without growing PostService into a catch-all.
+type CommentService struct {
+	session *AppOnlyOAuthSession
+}
@@ -0,0 +1,65 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestJSONDecoderDecode(t *testing.T) {
+	var v struct {
+		Name string `json:"name"`
+	}
+	if err := (jsonDecoder{}).Decode([]byte(`{"name":"geddit"}`), &v); err != nil {
+		t.Fatalf("Decode returned %v", err)
+	}
+	if v.Name != "geddit" {
+		t.Fatalf("Name = %q, want %q", v.Name, "geddit")
+	}
+}
+
+func TestDecodeListingEnvelope(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"kind": "Listing",
+			"data": {
+				"after": "t3_after",
+				"before": "",
+				"children": [
+					{"kind": "t3", "data": {"id": "a"}},
+					{"kind": "t3", "data": {"id": "b"}}
+				]
+			}
+		}`))
+	}))
+	defer srv.Close()
+
+	a := &AppOnlyOAuthSession{
+		Client:      srv.Client(),
+		TokenExpiry: time.Now().Add(time.Hour),
+		UserAgent:   "test",
+		RateLimiter: NewInMemoryRateLimiter(defaultRateLimitBuffer),
+		Decoder:     jsonDecoder{},
+	}
+
+	env, err := a.DecodeListingEnvelope(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("DecodeListingEnvelope returned %v", err)
+	}
+	if env.Kind != "Listing" || env.After != "t3_after" || env.Before != "" {
+		t.Fatalf("env = %+v, want kind=Listing after=t3_after before=\"\"", env)
+	}
+	if len(env.Children) != 2 {
+		t.Fatalf("len(Children) = %d, want 2", len(env.Children))
+	}
+	if got := string(env.Children[0].GetStringBytes("data", "id")); got != "a" {
+		t.Fatalf("Children[0].id = %q, want %q", got, "a")
+	}
+}
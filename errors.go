@@ -0,0 +1,63 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// Sentinel errors returned by AppOnlyOAuthSession methods. Callers should
+// match against these with errors.Is rather than inspecting status codes or
+// matching strings.
+var (
+	// ErrOAuthRevoked is returned when reddit rejects the bearer token
+	// (401, or a 403 with no subreddit-specific reason). The session
+	// forces a token refresh before its next request.
+	ErrOAuthRevoked = errors.New("geddit: oauth token revoked or invalid")
+	// ErrSubredditNotFound is returned when reddit responds 404 for a
+	// subreddit-scoped request.
+	ErrSubredditNotFound = errors.New("geddit: subreddit not found")
+	// ErrSubredditIsPrivate is returned when reddit's JSON error body
+	// reports the subreddit as private.
+	ErrSubredditIsPrivate = errors.New("geddit: subreddit is private")
+	// ErrSubredditIsQuarantined is returned when reddit's JSON error body
+	// reports the subreddit as quarantined.
+	ErrSubredditIsQuarantined = errors.New("geddit: subreddit is quarantined")
+	// ErrTimeout is returned when a request (including its retries)
+	// never received a response in time.
+	ErrTimeout = errors.New("geddit: request timed out")
+)
+
+// redditErrorBody is the shape of the JSON body reddit sends alongside 403s
+// for subreddits in a restricted state.
+type redditErrorBody struct {
+	Reason string `json:"reason"`
+}
+
+// classifyStatusError maps a non-2xx response to one of the sentinel errors
+// above, or nil if the response doesn't correspond to a condition geddit
+// knows how to classify (the caller falls back to a generic error).
+func classifyStatusError(resp *http.Response, body []byte) error {
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return ErrOAuthRevoked
+	case http.StatusForbidden:
+		var e redditErrorBody
+		if json.Unmarshal(body, &e) == nil {
+			switch e.Reason {
+			case "private":
+				return ErrSubredditIsPrivate
+			case "quarantined":
+				return ErrSubredditIsQuarantined
+			}
+		}
+		return ErrOAuthRevoked
+	case http.StatusNotFound:
+		return ErrSubredditNotFound
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestIsRetryableError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"oauth revoked", ErrOAuthRevoked, false},
+		{"subreddit not found", ErrSubredditNotFound, false},
+		{"subreddit private", ErrSubredditIsPrivate, false},
+		{"subreddit quarantined", ErrSubredditIsQuarantined, false},
+		{"rate limited", ErrRateLimited, false},
+		{"timeout", ErrTimeout, true},
+		{"generic network error", errors.New("connection reset"), true},
+	}
+	for _, c := range cases {
+		if got := isRetryableError(c.err); got != c.want {
+			t.Errorf("isRetryableError(%s) = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestIsRetryableErrorJSONSyntaxError(t *testing.T) {
+	var v interface{}
+	err := json.Unmarshal([]byte("not json"), &v)
+	if err == nil {
+		t.Fatal("expected a json.SyntaxError")
+	}
+	if isRetryableError(err) {
+		t.Error("isRetryableError(json.SyntaxError) = true, want false")
+	}
+}
+
+func TestClassifyStatusError(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+		body   string
+		want   error
+	}{
+		{"unauthorized", http.StatusUnauthorized, "", ErrOAuthRevoked},
+		{"not found", http.StatusNotFound, "", ErrSubredditNotFound},
+		{"forbidden private", http.StatusForbidden, `{"reason":"private"}`, ErrSubredditIsPrivate},
+		{"forbidden quarantined", http.StatusForbidden, `{"reason":"quarantined"}`, ErrSubredditIsQuarantined},
+		{"forbidden unknown reason", http.StatusForbidden, `{"reason":"other"}`, ErrOAuthRevoked},
+		{"unclassified", http.StatusTeapot, "", nil},
+	}
+	for _, c := range cases {
+		resp := &http.Response{StatusCode: c.status}
+		if got := classifyStatusError(resp, []byte(c.body)); got != c.want {
+			t.Errorf("classifyStatusError(%s) = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
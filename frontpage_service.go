@@ -0,0 +1,24 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import "golang.org/x/net/context"
+
+// FrontpageService groups endpoints for the default, logged-out-style
+// reddit frontpage. Obtain one from AppOnlyOAuthSession.Frontpage rather
+// than constructing it directly.
+type FrontpageService struct {
+	session *AppOnlyOAuthSession
+}
+
+// Hot returns a page of the hottest submissions on the frontpage.
+func (s *FrontpageService) Hot(ctx context.Context, params ListingOptions, opts ...RequestOption) (*SubmissionListing, error) {
+	return s.Sorted(ctx, popularitySort("hot"), params, opts...)
+}
+
+// Sorted returns a page of frontpage submissions under an arbitrary sort.
+func (s *FrontpageService) Sorted(ctx context.Context, sort popularitySort, params ListingOptions, opts ...RequestOption) (*SubmissionListing, error) {
+	return s.session.subredditSubmissions(ctx, "", sort, params, "", 0, opts...)
+}
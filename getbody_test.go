@@ -0,0 +1,40 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// TestGetBodyStopsOnCanceledContext checks that a retry loop facing a
+// canceled context returns promptly instead of sleeping through the full
+// backoff schedule.
+func TestGetBodyStopsOnCanceledContext(t *testing.T) {
+	a := &AppOnlyOAuthSession{
+		// Client is nil, so getBodyOnce fails immediately with a generic,
+		// retryable error. TokenExpiry is set far in the future so
+		// refreshToken short-circuits without touching OAuthConfig/TokenStore.
+		Client:      nil,
+		TokenExpiry: time.Now().Add(time.Hour),
+		UserAgent:   "test",
+		RateLimiter: NewInMemoryRateLimiter(defaultRateLimitBuffer),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	var d interface{}
+	err := a.getBody(ctx, "http://example.invalid", &d, WithBackoff([]time.Duration{time.Minute}))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("getBody took %v against a canceled context, want it to return promptly", elapsed)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import (
+	"errors"
+
+	"golang.org/x/net/context"
+)
+
+// ErrListingExhausted is returned by SubmissionListing.Next when a listing
+// has no further pages.
+var ErrListingExhausted = errors.New("geddit: listing exhausted")
+
+// Stop can be returned by the callback passed to All to end iteration
+// early without that being treated as a failure.
+var Stop = errors.New("geddit: stop iteration")
+
+// SubmissionListing is a page of submissions plus the pagination tokens
+// reddit.com returned alongside it.
+type SubmissionListing struct {
+	Submissions []*Submission
+	After       string
+	Before      string
+	// Count is the number of submissions reddit has seen across this
+	// listing so far, including this page; it is sent back as the count
+	// parameter on the next page so reddit can keep numbering consistent.
+	Count int
+
+	next func(ctx context.Context, after string, count int) (*SubmissionListing, error)
+}
+
+// Next fetches the page following this one, re-issuing the original
+// request with after/count merged in. It returns ErrListingExhausted once
+// After is empty.
+func (l *SubmissionListing) Next(ctx context.Context) (*SubmissionListing, error) {
+	if l == nil || l.After == "" || l.next == nil {
+		return nil, ErrListingExhausted
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return l.next(ctx, l.After, l.Count)
+}
+
+// All walks page after page of a listing starting at first, invoking fn
+// with each one, until the listing is exhausted or fn returns Stop. Any
+// other error from fn, or from fetching the next page, is returned to the
+// caller. Page fetches go through the session's RateLimiter like any other
+// request.
+func (a *AppOnlyOAuthSession) All(ctx context.Context, first *SubmissionListing, fn func(*SubmissionListing) error) error {
+	page := first
+	for page != nil {
+		if err := fn(page); err != nil {
+			if err == Stop {
+				return nil
+			}
+			return err
+		}
+		if page.After == "" {
+			return nil
+		}
+		next, err := page.Next(ctx)
+		if err != nil {
+			return err
+		}
+		page = next
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+)
+
+func fakePage(after string, count int, next func(ctx context.Context, after string, count int) (*SubmissionListing, error)) *SubmissionListing {
+	return &SubmissionListing{After: after, Count: count, next: next}
+}
+
+func TestSubmissionListingNextExhausted(t *testing.T) {
+	l := fakePage("", 3, nil)
+	if _, err := l.Next(context.Background()); err != ErrListingExhausted {
+		t.Fatalf("Next() = %v, want ErrListingExhausted", err)
+	}
+}
+
+func TestSubmissionListingNextPassesAfterAndCount(t *testing.T) {
+	var gotAfter string
+	var gotCount int
+	l := fakePage("tok", 3, func(ctx context.Context, after string, count int) (*SubmissionListing, error) {
+		gotAfter = after
+		gotCount = count
+		return &SubmissionListing{}, nil
+	})
+
+	if _, err := l.Next(context.Background()); err != nil {
+		t.Fatalf("Next() returned %v", err)
+	}
+	if gotAfter != "tok" || gotCount != 3 {
+		t.Fatalf("next called with after=%q count=%d, want after=%q count=%d", gotAfter, gotCount, "tok", 3)
+	}
+}
+
+func TestSubmissionListingNextRespectsCanceledContext(t *testing.T) {
+	l := fakePage("tok", 0, func(ctx context.Context, after string, count int) (*SubmissionListing, error) {
+		t.Fatal("next should not be called when ctx is already canceled")
+		return nil, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.Next(ctx); err != context.Canceled {
+		t.Fatalf("Next() = %v, want context.Canceled", err)
+	}
+}
+
+func TestAllWalksUntilExhausted(t *testing.T) {
+	a := &AppOnlyOAuthSession{}
+
+	var seen []string
+	var next func(ctx context.Context, after string, count int) (*SubmissionListing, error)
+	next = func(ctx context.Context, after string, count int) (*SubmissionListing, error) {
+		if after == "page3" {
+			return fakePage("", count, nil), nil
+		}
+		return fakePage("page3", count, next), nil
+	}
+	first := fakePage("page2", 0, next)
+
+	err := a.All(context.Background(), first, func(page *SubmissionListing) error {
+		seen = append(seen, page.After)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("All() returned %v", err)
+	}
+	if want := []string{"page2", "page3", ""}; !equalStrings(seen, want) {
+		t.Fatalf("visited %v, want %v", seen, want)
+	}
+}
+
+func TestAllStopsOnStop(t *testing.T) {
+	a := &AppOnlyOAuthSession{}
+	calls := 0
+	var next func(ctx context.Context, after string, count int) (*SubmissionListing, error)
+	next = func(ctx context.Context, after string, count int) (*SubmissionListing, error) {
+		return fakePage("more", 0, next), nil
+	}
+	first := fakePage("more", 0, next)
+
+	err := a.All(context.Background(), first, func(page *SubmissionListing) error {
+		calls++
+		if calls == 2 {
+			return Stop
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("All() returned %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("fn called %d times, want 2", calls)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
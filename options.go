@@ -0,0 +1,122 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import (
+	"net/http"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+)
+
+// Option configures an AppOnlyOAuthSession at construction time. See
+// NewAppOnlyOAuthSession.
+type Option func(*AppOnlyOAuthSession) error
+
+// Logger is the minimal logging interface geddit writes diagnostics to. Any
+// logger with a Printf method (including the standard library's *log.Logger)
+// satisfies this.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+type noopLogger struct{}
+
+func (noopLogger) Printf(string, ...interface{}) {}
+
+// WithHTTPClient sets the base *http.Client the OAuth config uses to mint
+// and use tokens. refreshToken always rebinds a.Client to one wrapping the
+// token source, so setting a.Client directly would be discarded on the
+// first refresh; this instead stashes client on the session's context,
+// the same mechanism WithTransport and WithConnectionLimit use, so it
+// survives every refresh.
+func WithHTTPClient(client *http.Client) Option {
+	return func(a *AppOnlyOAuthSession) error {
+		a.ctx = context.WithValue(a.ctx, oauth2.HTTPClient, client)
+		return nil
+	}
+}
+
+// WithUserAgent overrides the User-Agent sent on every request.
+func WithUserAgent(ua string) Option {
+	return func(a *AppOnlyOAuthSession) error {
+		a.UserAgent = ua
+		return nil
+	}
+}
+
+// WithTransport sets the http.RoundTripper the OAuth config uses to mint and
+// use tokens.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(a *AppOnlyOAuthSession) error {
+		a.ctx = context.WithValue(a.ctx, oauth2.HTTPClient, &http.Client{Transport: rt})
+		return nil
+	}
+}
+
+// WithBaseURL overrides reddit's OAuth API root, e.g. to point at a mock
+// server or proxy in tests.
+func WithBaseURL(baseURL string) Option {
+	return func(a *AppOnlyOAuthSession) error {
+		a.BaseURL = baseURL
+		return nil
+	}
+}
+
+// WithLogger sets the Logger diagnostics are written to.
+func WithLogger(logger Logger) Option {
+	return func(a *AppOnlyOAuthSession) error {
+		a.Logger = logger
+		return nil
+	}
+}
+
+// WithRequestCallback registers a hook invoked with every request/response
+// pair, letting callers wire in metrics or tracing without geddit depending
+// on any specific observability stack.
+func WithRequestCallback(cb func(*http.Request, *http.Response)) Option {
+	return func(a *AppOnlyOAuthSession) error {
+		a.RequestCallback = cb
+		return nil
+	}
+}
+
+// WithDecoder overrides how response bodies are decoded into the caller's
+// structs. The default Decoder wraps encoding/json.
+func WithDecoder(d Decoder) Option {
+	return func(a *AppOnlyOAuthSession) error {
+		a.Decoder = d
+		return nil
+	}
+}
+
+// WithTokenStore overrides where the minted OAuth token is cached. See
+// NewRedisTokenStore to share one token across a fleet of processes using
+// the same client credentials.
+func WithTokenStore(store TokenStore) Option {
+	return func(a *AppOnlyOAuthSession) error {
+		a.TokenStore = store
+		return nil
+	}
+}
+
+// WithConnectionLimit tunes the transport for a given number of concurrent
+// connections: MaxConnsPerHost is set to limit, MaxIdleConns and
+// MaxIdleConnsPerHost to limit as well so idle connections are reused rather
+// than closed and reopened.
+func WithConnectionLimit(limit int) Option {
+	return func(a *AppOnlyOAuthSession) error {
+		transport := &http.Transport{
+			MaxIdleConns:          limit,
+			MaxConnsPerHost:       limit,
+			MaxIdleConnsPerHost:   limit,
+			IdleConnTimeout:       60 * time.Second,
+			ResponseHeaderTimeout: 5 * time.Second,
+		}
+		a.ctx = context.WithValue(a.ctx, oauth2.HTTPClient, &http.Client{Transport: transport})
+		return nil
+	}
+}
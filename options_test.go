@@ -0,0 +1,66 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+)
+
+// recordingRoundTripper wraps another RoundTripper, noting whether it was
+// used to satisfy a request.
+type recordingRoundTripper struct {
+	used bool
+	rt   http.RoundTripper
+}
+
+func (r *recordingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.used = true
+	return r.rt.RoundTrip(req)
+}
+
+// TestWithHTTPClientSurvivesRefresh checks that the *http.Client passed to
+// WithHTTPClient is still the one backing outgoing requests after
+// refreshToken has rebound a.Client to the token source, instead of being
+// silently discarded on the first refresh.
+func TestWithHTTPClientSurvivesRefresh(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	rt := &recordingRoundTripper{rt: http.DefaultTransport}
+	a, err := NewAppOnlyOAuthSession("id", "secret", "test", false,
+		WithHTTPClient(&http.Client{Transport: rt}),
+	)
+	if err != nil {
+		t.Fatalf("NewAppOnlyOAuthSession returned %v", err)
+	}
+
+	// Prime the TokenStore so refreshToken takes the cache-hit path rather
+	// than minting a token against the real reddit endpoint.
+	a.TokenStore.Set(context.Background(), a.ClientID, &oauth2.Token{
+		AccessToken: "cached",
+		Expiry:      time.Now().Add(time.Hour),
+	})
+	if err := a.refreshToken(context.Background()); err != nil {
+		t.Fatalf("refreshToken returned %v", err)
+	}
+
+	resp, err := a.Client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Client.Get returned %v", err)
+	}
+	resp.Body.Close()
+
+	if !rt.used {
+		t.Fatal("request did not go through the WithHTTPClient RoundTripper; it was discarded by refreshToken")
+	}
+}
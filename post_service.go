@@ -0,0 +1,18 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import "golang.org/x/net/context"
+
+// PostService groups the endpoints scoped to a single submission. Obtain
+// one from AppOnlyOAuthSession.Post rather than constructing it directly.
+type PostService struct {
+	session *AppOnlyOAuthSession
+}
+
+// Comments returns the comment tree for the submission with the given ID.
+func (s *PostService) Comments(ctx context.Context, id string, sort popularitySort, params ListingOptions, opts ...RequestOption) ([]*Comment, error) {
+	return s.session.comments(ctx, id, sort, params, opts...)
+}
@@ -0,0 +1,143 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// defaultRateLimitBuffer is the remaining-request budget below which a
+// session will start throttling its own requests.
+const defaultRateLimitBuffer = 50
+
+type rateLimitContextKey int
+
+const (
+	skipRateLimitingKey rateLimitContextKey = iota
+	nonBlockingRateLimitKey
+)
+
+// SkipRateLimiting returns a context that causes the session to bypass the
+// rate limiter entirely. Use this for admin/health-check calls that must
+// always go through regardless of the shared request budget.
+func SkipRateLimiting(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipRateLimitingKey, true)
+}
+
+// NonBlockingRateLimit returns a context that causes the session to return
+// ErrRateLimited instead of sleeping when the request budget is exhausted.
+func NonBlockingRateLimit(ctx context.Context) context.Context {
+	return context.WithValue(ctx, nonBlockingRateLimitKey, true)
+}
+
+// RateLimitingInfo captures the X-Ratelimit-* headers reddit.com includes on
+// every OAuth response.
+type RateLimitingInfo struct {
+	// Remaining is the number of requests left in the current window.
+	Remaining float64
+	// Used is the number of requests already made in the current window.
+	Used int
+	// Reset is the number of seconds until the window resets.
+	Reset int
+	// Timestamp is when this info was recorded, used to compute how much of
+	// Reset has already elapsed.
+	Timestamp time.Time
+	// Present reports whether the last response actually carried
+	// rate-limit headers (reddit omits them for a handful of endpoints).
+	Present bool
+}
+
+// ErrRateLimited is returned when a caller has opted into non-blocking mode
+// via NonBlockingRateLimit and the request budget is exhausted.
+var ErrRateLimited = errors.New("geddit: rate limited")
+
+// RateLimiter decides whether a request may proceed and is kept informed of
+// the rate-limit headers reddit returns. Implementations must be safe for
+// concurrent use so that many process instances sharing client credentials
+// (e.g. via a Redis-backed implementation) can cooperate on one budget.
+type RateLimiter interface {
+	// Take blocks (or returns ErrRateLimited in non-blocking mode, see
+	// NonBlockingRateLimit) until a request for key is allowed to proceed.
+	Take(ctx context.Context, key string) error
+	// Update records the rate-limit headers from a response.
+	Update(info RateLimitingInfo)
+}
+
+// inMemoryRateLimiter is the default RateLimiter: it tracks a single
+// process's view of the budget with no external coordination.
+type inMemoryRateLimiter struct {
+	buffer float64
+
+	mu   sync.Mutex
+	info RateLimitingInfo
+}
+
+// NewInMemoryRateLimiter returns a RateLimiter that throttles once fewer
+// than buffer requests remain in the current window. A buffer of 0 uses
+// defaultRateLimitBuffer.
+func NewInMemoryRateLimiter(buffer float64) RateLimiter {
+	if buffer <= 0 {
+		buffer = defaultRateLimitBuffer
+	}
+	return &inMemoryRateLimiter{buffer: buffer}
+}
+
+func (l *inMemoryRateLimiter) Take(ctx context.Context, key string) error {
+	l.mu.Lock()
+	info := l.info
+	l.mu.Unlock()
+
+	if !info.Present || info.Remaining >= l.buffer {
+		return nil
+	}
+
+	wait := time.Duration(info.Reset)*time.Second - time.Since(info.Timestamp)
+	if wait <= 0 {
+		return nil
+	}
+
+	if ctx.Value(nonBlockingRateLimitKey) != nil {
+		return ErrRateLimited
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *inMemoryRateLimiter) Update(info RateLimitingInfo) {
+	l.mu.Lock()
+	l.info = info
+	l.mu.Unlock()
+}
+
+// parseRateLimitingInfo builds a RateLimitingInfo from the headers of a
+// reddit OAuth response. Present is false when none of the headers were
+// sent, which reddit does for a handful of endpoints.
+func parseRateLimitingInfo(header interface{ Get(string) string }) RateLimitingInfo {
+	remaining := header.Get("x-ratelimit-remaining")
+	used := header.Get("x-ratelimit-used")
+	reset := header.Get("x-ratelimit-reset")
+
+	if remaining == "" && used == "" && reset == "" {
+		return RateLimitingInfo{}
+	}
+
+	info := RateLimitingInfo{Present: true, Timestamp: time.Now()}
+	info.Remaining, _ = strconv.ParseFloat(remaining, 64)
+	info.Used, _ = strconv.Atoi(used)
+	info.Reset, _ = strconv.Atoi(reset)
+	return info
+}
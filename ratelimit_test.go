@@ -0,0 +1,54 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestInMemoryRateLimiterTakeUnderBuffer(t *testing.T) {
+	l := NewInMemoryRateLimiter(50)
+	l.Update(RateLimitingInfo{Present: true, Remaining: 100, Reset: 60, Timestamp: time.Now()})
+
+	if err := l.Take(context.Background(), "client"); err != nil {
+		t.Fatalf("Take returned %v, want nil", err)
+	}
+}
+
+func TestInMemoryRateLimiterNonBlockingReturnsErrRateLimited(t *testing.T) {
+	l := NewInMemoryRateLimiter(50)
+	l.Update(RateLimitingInfo{Present: true, Remaining: 1, Reset: 60, Timestamp: time.Now()})
+
+	ctx := NonBlockingRateLimit(context.Background())
+	if err := l.Take(ctx, "client"); err != ErrRateLimited {
+		t.Fatalf("Take returned %v, want ErrRateLimited", err)
+	}
+}
+
+func TestParseRateLimitingInfoAbsentHeaders(t *testing.T) {
+	info := parseRateLimitingInfo(http.Header{})
+	if info.Present {
+		t.Fatalf("Present = true, want false for a response with no rate-limit headers")
+	}
+}
+
+func TestParseRateLimitingInfo(t *testing.T) {
+	h := http.Header{}
+	h.Set("x-ratelimit-remaining", "42.0")
+	h.Set("x-ratelimit-used", "8")
+	h.Set("x-ratelimit-reset", "60")
+
+	info := parseRateLimitingInfo(h)
+	if !info.Present {
+		t.Fatal("Present = false, want true")
+	}
+	if info.Remaining != 42.0 || info.Used != 8 || info.Reset != 60 {
+		t.Fatalf("parsed %+v, want Remaining=42 Used=8 Reset=60", info)
+	}
+}
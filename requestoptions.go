@@ -0,0 +1,44 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import "time"
+
+// defaultBackoffSchedule is the delay before each retry of a request that
+// failed with a transient error.
+var defaultBackoffSchedule = []time.Duration{
+	200 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2 * time.Second,
+}
+
+// requestConfig holds the per-request settings a RequestOption can tweak.
+type requestConfig struct {
+	retry   bool
+	backoff []time.Duration
+}
+
+func defaultRequestConfig() requestConfig {
+	return requestConfig{retry: true, backoff: defaultBackoffSchedule}
+}
+
+// RequestOption tweaks the retry behavior of a single API call.
+type RequestOption func(*requestConfig)
+
+// WithNoRetry disables retries for a single call, so the first transient
+// failure is returned to the caller immediately.
+func WithNoRetry() RequestOption {
+	return func(c *requestConfig) { c.retry = false }
+}
+
+// WithBackoff overrides the default backoff schedule for a single call.
+// len(schedule) is the maximum number of retries.
+func WithBackoff(schedule []time.Duration) RequestOption {
+	return func(c *requestConfig) {
+		c.retry = true
+		c.backoff = schedule
+	}
+}
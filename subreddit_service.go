@@ -0,0 +1,25 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import "golang.org/x/net/context"
+
+// SubredditService groups the endpoints scoped to a single subreddit.
+// Obtain one from AppOnlyOAuthSession.Subreddit rather than constructing it
+// directly.
+type SubredditService struct {
+	session *AppOnlyOAuthSession
+}
+
+// About returns the subreddit for the given name.
+func (s *SubredditService) About(ctx context.Context, name string, opts ...RequestOption) (*Subreddit, error) {
+	return s.session.aboutSubreddit(ctx, name, opts...)
+}
+
+// Submissions returns a page of submissions on the given subreddit, sorted
+// as requested.
+func (s *SubredditService) Submissions(ctx context.Context, name string, sort popularitySort, params ListingOptions, opts ...RequestOption) (*SubmissionListing, error) {
+	return s.session.subredditSubmissions(ctx, name, sort, params, "", 0, opts...)
+}
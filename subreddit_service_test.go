@@ -0,0 +1,61 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// TestSubredditServiceAboutThreadsContext checks that SubredditService.About
+// passes its ctx through to getBody rather than silently swapping in
+// context.Background(), so a caller's cancellation actually takes effect.
+func TestSubredditServiceAboutThreadsContext(t *testing.T) {
+	a := &AppOnlyOAuthSession{
+		Client:      nil,
+		TokenExpiry: time.Now().Add(time.Hour),
+		UserAgent:   "test",
+		RateLimiter: NewInMemoryRateLimiter(defaultRateLimitBuffer),
+	}
+	svc := &SubredditService{session: a}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	if _, err := svc.About(ctx, "golang", WithBackoff([]time.Duration{time.Minute})); err != ctx.Err() {
+		t.Fatalf("About() = %v, want %v", err, ctx.Err())
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("About took %v against a canceled context, want it to return promptly", elapsed)
+	}
+}
+
+// TestAboutSubredditDelegatesToService checks that the deprecated top-level
+// AboutSubreddit wrapper delegates to the same aboutSubreddit helper the
+// context-aware service method uses, rather than diverging in behavior.
+func TestAboutSubredditDelegatesToService(t *testing.T) {
+	// Keep the default retry schedule's delays out of this test; only the
+	// delegation matters here, not the retry behavior getBody covers
+	// elsewhere.
+	orig := defaultBackoffSchedule
+	defaultBackoffSchedule = nil
+	defer func() { defaultBackoffSchedule = orig }()
+
+	a := &AppOnlyOAuthSession{
+		Client:      nil,
+		TokenExpiry: time.Now().Add(time.Hour),
+		UserAgent:   "test",
+		RateLimiter: NewInMemoryRateLimiter(defaultRateLimitBuffer),
+	}
+
+	_, wantErr := a.aboutSubreddit(context.Background(), "golang")
+	_, gotErr := a.AboutSubreddit("golang")
+	if gotErr.Error() != wantErr.Error() {
+		t.Fatalf("AboutSubreddit() = %v, want %v", gotErr, wantErr)
+	}
+}
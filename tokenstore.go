@@ -0,0 +1,52 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+)
+
+// tokenRefreshSkew is how far before a token's real expiry geddit
+// considers it already expired, to avoid racing reddit's clock or another
+// process's renewal.
+const tokenRefreshSkew = 30 * time.Second
+
+// TokenStore persists the OAuth token backing a set of client credentials,
+// so that a fleet of workers sharing those credentials can reuse one token
+// instead of each independently hitting /api/v1/access_token and tripping
+// reddit's auth rate limit. Set it via WithTokenStore.
+type TokenStore interface {
+	Get(ctx context.Context, clientID string) (*oauth2.Token, error)
+	Set(ctx context.Context, clientID string, token *oauth2.Token) error
+}
+
+// memoryTokenStore is the default TokenStore: a single process's token,
+// held in memory.
+type memoryTokenStore struct {
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+// NewMemoryTokenStore returns a TokenStore scoped to this process only.
+func NewMemoryTokenStore() TokenStore {
+	return &memoryTokenStore{}
+}
+
+func (s *memoryTokenStore) Get(ctx context.Context, clientID string) (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token, nil
+}
+
+func (s *memoryTokenStore) Set(ctx context.Context, clientID string, token *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+	return nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+)
+
+// redisTokenStore persists the OAuth token in Redis, keyed by client ID,
+// so that many process instances sharing one set of client credentials
+// cooperate on a single token.
+type redisTokenStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisTokenStore returns a TokenStore backed by client. Keys are
+// stored as prefix+clientID; prefix defaults to "geddit:token:" when
+// empty.
+func NewRedisTokenStore(client *redis.Client, prefix string) TokenStore {
+	if prefix == "" {
+		prefix = "geddit:token:"
+	}
+	return &redisTokenStore{client: client, prefix: prefix}
+}
+
+func (s *redisTokenStore) key(clientID string) string {
+	return s.prefix + clientID
+}
+
+func (s *redisTokenStore) Get(ctx context.Context, clientID string) (*oauth2.Token, error) {
+	data, err := s.client.Get(ctx, s.key(clientID)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var t oauth2.Token
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (s *redisTokenStore) Set(ctx context.Context, clientID string, token *oauth2.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	// Expire the cached token a little before it actually expires, so a
+	// cold cache always forces a real refresh rather than handing out a
+	// token reddit is about to reject.
+	ttl := time.Until(token.Expiry) - tokenRefreshSkew
+	if ttl <= 0 {
+		return nil
+	}
+	return s.client.Set(ctx, s.key(clientID), data, ttl).Err()
+}
@@ -0,0 +1,96 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"golang.org/x/oauth2"
+)
+
+func TestMemoryTokenStoreGetEmpty(t *testing.T) {
+	s := NewMemoryTokenStore()
+	tok, err := s.Get(context.Background(), "client")
+	if err != nil {
+		t.Fatalf("Get returned %v", err)
+	}
+	if tok != nil {
+		t.Fatalf("Get = %+v, want nil on an empty store", tok)
+	}
+}
+
+func TestMemoryTokenStoreSetGet(t *testing.T) {
+	s := NewMemoryTokenStore()
+	want := &oauth2.Token{AccessToken: "abc", Expiry: time.Now().Add(time.Hour)}
+
+	if err := s.Set(context.Background(), "client", want); err != nil {
+		t.Fatalf("Set returned %v", err)
+	}
+	got, err := s.Get(context.Background(), "client")
+	if err != nil {
+		t.Fatalf("Get returned %v", err)
+	}
+	if got.AccessToken != want.AccessToken {
+		t.Fatalf("Get = %+v, want %+v", got, want)
+	}
+}
+
+func TestMemoryTokenStoreConcurrentAccess(t *testing.T) {
+	s := NewMemoryTokenStore()
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Set(context.Background(), "client", &oauth2.Token{AccessToken: "tok"})
+			s.Get(context.Background(), "client")
+		}()
+	}
+	wg.Wait()
+}
+
+// TestRefreshTokenSerializesConcurrentCallers checks that concurrent
+// refreshToken calls against a primed TokenStore all succeed under the
+// session lock, none of them reaching OAuthConfig, and leave Client set.
+func TestRefreshTokenSerializesConcurrentCallers(t *testing.T) {
+	a := &AppOnlyOAuthSession{
+		ClientID:   "client",
+		ctx:        context.Background(),
+		Logger:     noopLogger{},
+		TokenStore: NewMemoryTokenStore(),
+	}
+	a.OAuthConfig = nil // refreshToken must not reach OAuthConfig once another
+	// goroutine has already populated TokenExpiry/Client under the lock.
+
+	// Prime the store so the very first refreshToken call takes the
+	// TokenStore-hit path instead of panicking on a nil OAuthConfig.
+	a.TokenStore.Set(context.Background(), "client", &oauth2.Token{
+		AccessToken: "primed",
+		Expiry:      time.Now().Add(time.Hour),
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- a.refreshToken(context.Background())
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("refreshToken returned %v", err)
+		}
+	}
+	if a.Client == nil {
+		t.Fatal("Client was never set")
+	}
+}
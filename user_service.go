@@ -0,0 +1,34 @@
+// Copyright 2016 Samir Bhatt. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package geddit
+
+import "golang.org/x/net/context"
+
+// UserService groups the endpoints scoped to a single redditor. Obtain one
+// from AppOnlyOAuthSession.User rather than constructing it directly.
+type UserService struct {
+	session *AppOnlyOAuthSession
+}
+
+// About returns the Redditor for the given username.
+func (s *UserService) About(ctx context.Context, user string, opts ...RequestOption) (*Redditor, error) {
+	return s.session.aboutRedditor(ctx, user, opts...)
+}
+
+// Trophies returns the trophies a user has been awarded.
+func (s *UserService) Trophies(ctx context.Context, user string, opts ...RequestOption) ([]*Trophy, error) {
+	return s.session.userTrophies(ctx, user, opts...)
+}
+
+// Upvoted returns a page of the submissions a user has upvoted.
+func (s *UserService) Upvoted(ctx context.Context, user string, sort popularitySort, params ListingOptions, opts ...RequestOption) (*SubmissionListing, error) {
+	return s.session.userListing(ctx, user, "upvoted", sort, params, "", 0, opts...)
+}
+
+// Listing returns a page of an arbitrary named listing on a user's
+// profile, e.g. "submitted" or "gilded".
+func (s *UserService) Listing(ctx context.Context, user, listing string, sort popularitySort, params ListingOptions, opts ...RequestOption) (*SubmissionListing, error) {
+	return s.session.userListing(ctx, user, listing, sort, params, "", 0, opts...)
+}